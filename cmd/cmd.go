@@ -3,10 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
-	"crypto/ed25519"
-	"crypto/rand"
 	"crypto/sha256"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
@@ -24,11 +21,11 @@ import (
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"golang.org/x/crypto/ssh"
 	"golang.org/x/term"
 
 	"github.com/jmorganca/ollama/api"
 	"github.com/jmorganca/ollama/format"
+	_ "github.com/jmorganca/ollama/llm/stablediffusion" // registers the image backend with llm.NewImage
 	"github.com/jmorganca/ollama/parser"
 	"github.com/jmorganca/ollama/progress"
 	"github.com/jmorganca/ollama/server"
@@ -635,45 +632,14 @@ func RunServer(cmd *cobra.Command, _ []string) error {
 }
 
 func initializeKeypair() error {
-	home, err := os.UserHomeDir()
+	privKeyPath, pubKeyPath, err := keyPaths()
 	if err != nil {
 		return err
 	}
 
-	privKeyPath := filepath.Join(home, ".ollama", "id_ed25519")
-	pubKeyPath := filepath.Join(home, ".ollama", "id_ed25519.pub")
-
-	_, err = os.Stat(privKeyPath)
-	if os.IsNotExist(err) {
+	if _, err := os.Stat(privKeyPath); os.IsNotExist(err) {
 		fmt.Printf("Couldn't find '%s'. Generating new private key.\n", privKeyPath)
-		_, privKey, err := ed25519.GenerateKey(rand.Reader)
-		if err != nil {
-			return err
-		}
-
-		privKeyBytes, err := format.OpenSSHPrivateKey(privKey, "")
-		if err != nil {
-			return err
-		}
-
-		err = os.MkdirAll(filepath.Dir(privKeyPath), 0o755)
-		if err != nil {
-			return fmt.Errorf("could not create directory %w", err)
-		}
-
-		err = os.WriteFile(privKeyPath, pem.EncodeToMemory(privKeyBytes), 0o600)
-		if err != nil {
-			return err
-		}
-
-		sshPrivateKey, err := ssh.NewSignerFromKey(privKey)
-		if err != nil {
-			return err
-		}
-
-		pubKeyData := ssh.MarshalAuthorizedKey(sshPrivateKey.PublicKey())
-
-		err = os.WriteFile(pubKeyPath, pubKeyData, 0o644)
+		pubKeyData, err := generateKeypair(privKeyPath, pubKeyPath)
 		if err != nil {
 			return err
 		}
@@ -922,6 +888,66 @@ func NewCLI() *cobra.Command {
 		ValidArgsFunction: autocompleteModelName,
 	}
 
+	keysCmd := &cobra.Command{
+		Use:               "keys",
+		Short:             "Manage the identity key used to authenticate model pushes",
+		ValidArgsFunction: doNotAutocomplete,
+	}
+
+	keysShowCmd := &cobra.Command{
+		Use:               "show",
+		Short:             "Show the current public key and its fingerprint",
+		Args:              cobra.ExactArgs(0),
+		RunE:              KeysShowHandler,
+		ValidArgsFunction: doNotAutocomplete,
+	}
+
+	keysRotateCmd := &cobra.Command{
+		Use:               "rotate",
+		Short:             "Back up the current key and generate a new one",
+		Args:              cobra.ExactArgs(0),
+		RunE:              KeysRotateHandler,
+		ValidArgsFunction: doNotAutocomplete,
+	}
+	keysRotateCmd.Flags().Bool("force", false, "Rotate even if a key already exists")
+
+	keysImportCmd := &cobra.Command{
+		Use:               "import PATH",
+		Short:             "Install an existing OpenSSH-format private key",
+		Args:              cobra.ExactArgs(1),
+		RunE:              KeysImportHandler,
+		ValidArgsFunction: doNotAutocomplete,
+	}
+	keysImportCmd.Flags().Bool("force", false, "Overwrite an existing key")
+
+	keysEnrollCmd := &cobra.Command{
+		Use:               "enroll REGISTRY",
+		Short:             "Associate this machine's public key with your account on a registry",
+		Args:              cobra.ExactArgs(1),
+		RunE:              KeysEnrollHandler,
+		ValidArgsFunction: doNotAutocomplete,
+	}
+
+	keysCmd.AddCommand(
+		keysShowCmd,
+		keysRotateCmd,
+		keysImportCmd,
+		keysEnrollCmd,
+	)
+
+	supportCmd := &cobra.Command{
+		Use:               "support",
+		Short:             "Create a diagnostic bundle for bug reports",
+		Args:              cobra.ExactArgs(0),
+		PreRunE:           checkServerHeartbeat,
+		RunE:              SupportHandler,
+		ValidArgsFunction: doNotAutocomplete,
+	}
+
+	supportCmd.Flags().String("output", "", "Path to write the bundle (default a timestamped file)")
+	supportCmd.Flags().Bool("stdout", false, "Stream the bundle to stdout instead of writing a file")
+	supportCmd.Flags().Bool("redact", false, "Strip environment variables and public key material")
+
 	completionCmd := &cobra.Command{
 		Use:                   "completion [bash|zsh|fish]",
 		Short:                 "Generate completion scripts",
@@ -944,6 +970,8 @@ func NewCLI() *cobra.Command {
 		listCmd,
 		copyCmd,
 		deleteCmd,
+		supportCmd,
+		keysCmd,
 		completionCmd,
 	)
 
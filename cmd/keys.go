@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/jmorganca/ollama/format"
+)
+
+func keyPaths() (priv, pub string, err error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	return filepath.Join(home, ".ollama", "id_ed25519"), filepath.Join(home, ".ollama", "id_ed25519.pub"), nil
+}
+
+// KeysShowHandler prints the current public key and its SHA256 fingerprint,
+// mirroring the format initializeKeypair writes on first 'serve'.
+func KeysShowHandler(cmd *cobra.Command, _ []string) error {
+	_, pubKeyPath, err := keyPaths()
+	if err != nil {
+		return err
+	}
+
+	pubKeyData, err := os.ReadFile(pubKeyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no key found at %s, run 'ollama serve' once to generate one", pubKeyPath)
+		}
+		return err
+	}
+
+	fingerprint, err := keyFingerprint(pubKeyData)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", string(pubKeyData))
+	fmt.Printf("SHA256:%s\n", fingerprint)
+	return nil
+}
+
+// KeysRotateHandler backs up the existing keypair and generates a fresh one.
+func KeysRotateHandler(cmd *cobra.Command, _ []string) error {
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	privKeyPath, pubKeyPath, err := keyPaths()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(privKeyPath); err == nil {
+		if !force {
+			return fmt.Errorf("a key already exists at %s, use --force to rotate it", privKeyPath)
+		}
+
+		backupPath := fmt.Sprintf("%s.%d.bak", privKeyPath, time.Now().Unix())
+		fmt.Printf("an existing key was found at %s, backing it up to %s\n", privKeyPath, backupPath)
+
+		if err := os.Rename(privKeyPath, backupPath); err != nil {
+			return err
+		}
+		if err := os.Rename(pubKeyPath, backupPath+".pub"); err != nil {
+			return err
+		}
+	}
+
+	pubKeyData, err := generateKeypair(privKeyPath, pubKeyPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Your new public key is: \n\n%s\n", string(pubKeyData))
+	fmt.Println("warning: any models you previously pushed remain tied to your old public key")
+	return nil
+}
+
+// KeysImportHandler installs an existing OpenSSH-format private key.
+func KeysImportHandler(cmd *cobra.Command, args []string) error {
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	privKeyPath, pubKeyPath, err := keyPaths()
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		if _, err := os.Stat(privKeyPath); err == nil {
+			return fmt.Errorf("a key already exists at %s, use --force to overwrite", privKeyPath)
+		}
+	}
+
+	keyData, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	signer, err := ssh.ParseRawPrivateKey(keyData)
+	if err != nil {
+		return fmt.Errorf("could not parse %s as an OpenSSH private key: %w", args[0], err)
+	}
+
+	privKey, ok := signer.(*ed25519.PrivateKey)
+	if !ok {
+		return fmt.Errorf("%s is not an ed25519 private key", args[0])
+	}
+
+	sshSigner, err := ssh.NewSignerFromKey(*privKey)
+	if err != nil {
+		return err
+	}
+	pubKeyData := ssh.MarshalAuthorizedKey(sshSigner.PublicKey())
+
+	if err := os.MkdirAll(filepath.Dir(privKeyPath), 0o755); err != nil {
+		return fmt.Errorf("could not create directory %w", err)
+	}
+
+	if err := os.WriteFile(privKeyPath, keyData, 0o600); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(pubKeyPath, pubKeyData, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("imported key, public key is: \n\n%s\n", string(pubKeyData))
+	fmt.Println("warning: any models you previously pushed remain tied to your old public key")
+	return nil
+}
+
+// KeysEnrollHandler associates this machine's public key with the user's
+// account on the given registry via a challenge-response flow.
+func KeysEnrollHandler(cmd *cobra.Command, args []string) error {
+	_, pubKeyPath, err := keyPaths()
+	if err != nil {
+		return err
+	}
+
+	pubKeyData, err := os.ReadFile(pubKeyPath)
+	if err != nil {
+		return fmt.Errorf("no key found at %s, run 'ollama serve' once to generate one", pubKeyPath)
+	}
+
+	registry := args[0]
+
+	challengeResp, err := http.Post(fmt.Sprintf("https://%s/v2/keys/challenge", registry), "application/json", bytes.NewReader(pubKeyData))
+	if err != nil {
+		return fmt.Errorf("could not reach registry: %w", err)
+	}
+	defer challengeResp.Body.Close()
+
+	if challengeResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry returned status %s", challengeResp.Status)
+	}
+
+	privKeyPath, _, err := keyPaths()
+	if err != nil {
+		return err
+	}
+
+	privKeyData, err := os.ReadFile(privKeyPath)
+	if err != nil {
+		return err
+	}
+
+	signer, err := ssh.ParsePrivateKey(privKeyData)
+	if err != nil {
+		return err
+	}
+
+	var challenge bytes.Buffer
+	if _, err := challenge.ReadFrom(challengeResp.Body); err != nil {
+		return err
+	}
+
+	sig, err := signer.Sign(rand.Reader, challenge.Bytes())
+	if err != nil {
+		return err
+	}
+
+	enrollResp, err := http.Post(fmt.Sprintf("https://%s/v2/keys/enroll", registry), "application/octet-stream", bytes.NewReader(ssh.Marshal(sig)))
+	if err != nil {
+		return fmt.Errorf("could not reach registry: %w", err)
+	}
+	defer enrollResp.Body.Close()
+
+	if enrollResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("enrollment failed: registry returned status %s", enrollResp.Status)
+	}
+
+	fmt.Printf("enrolled this machine's key with %s\n", registry)
+	return nil
+}
+
+func keyFingerprint(pubKeyData []byte) (string, error) {
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey(pubKeyData)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(pubKey.Marshal())
+	return base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
+func generateKeypair(privKeyPath, pubKeyPath string) ([]byte, error) {
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	privKeyBytes, err := format.OpenSSHPrivateKey(privKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(privKeyPath), 0o755); err != nil {
+		return nil, fmt.Errorf("could not create directory %w", err)
+	}
+
+	if err := os.WriteFile(privKeyPath, pem.EncodeToMemory(privKeyBytes), 0o600); err != nil {
+		return nil, err
+	}
+
+	sshPrivateKey, err := ssh.NewSignerFromKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeyData := ssh.MarshalAuthorizedKey(sshPrivateKey.PublicKey())
+	if err := os.WriteFile(pubKeyPath, pubKeyData, 0o644); err != nil {
+		return nil, err
+	}
+
+	return pubKeyData, nil
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jmorganca/ollama/api"
+	"github.com/jmorganca/ollama/gpu"
+	"github.com/jmorganca/ollama/version"
+)
+
+// SupportHandler collects a diagnostic bundle containing version, model, and
+// system information so users can attach a single file to a bug report
+// instead of hand-collecting output from 'show', 'list', and server logs.
+func SupportHandler(cmd *cobra.Command, _ []string) error {
+	client, err := api.ClientFromEnvironment()
+	if err != nil {
+		return err
+	}
+
+	redact, err := cmd.Flags().GetBool("redact")
+	if err != nil {
+		return err
+	}
+
+	files, err := collectSupportFiles(cmd.Context(), client, redact)
+	if err != nil {
+		return err
+	}
+
+	toStdout, err := cmd.Flags().GetBool("stdout")
+	if err != nil {
+		return err
+	}
+
+	if toStdout {
+		return writeSupportTarGz(os.Stdout, files)
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = fmt.Sprintf("ollama-support-%s.zip", time.Now().Format("20060102-150405"))
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := writeSupportZip(out, files); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote support bundle to %s\n", output)
+	return nil
+}
+
+// supportFile is a single named entry to be written into the bundle.
+type supportFile struct {
+	name string
+	data []byte
+}
+
+func collectSupportFiles(ctx context.Context, client *api.Client, redact bool) ([]supportFile, error) {
+	var files []supportFile
+
+	files = append(files, supportFile{"version.txt", []byte(fmt.Sprintf("client: %s\n", version.Version))})
+
+	if serverVersion, err := client.Version(ctx); err == nil {
+		files = append(files, supportFile{"server_version.txt", []byte(serverVersion + "\n")})
+	}
+
+	if models, err := client.List(ctx); err == nil {
+		var sb strings.Builder
+		for _, m := range models.Models {
+			fmt.Fprintf(&sb, "%s\t%s\t%d\n", m.Name, m.Digest, m.Size)
+
+			show, err := client.Show(ctx, &api.ShowRequest{Name: m.Name})
+			if err != nil {
+				continue
+			}
+
+			name := strings.NewReplacer("/", "_", ":", "_").Replace(m.Name)
+			files = append(files, supportFile{filepath.Join("models", name, "modelfile.txt"), []byte(show.Modelfile)})
+			files = append(files, supportFile{filepath.Join("models", name, "parameters.txt"), []byte(show.Parameters)})
+			files = append(files, supportFile{filepath.Join("models", name, "template.txt"), []byte(show.Template)})
+			files = append(files, supportFile{filepath.Join("models", name, "system.txt"), []byte(show.System)})
+			files = append(files, supportFile{filepath.Join("models", name, "license.txt"), []byte(show.License)})
+		}
+		files = append(files, supportFile{"models.txt", []byte(sb.String())})
+	}
+
+	if pubKey, err := supportPublicKey(); err == nil {
+		if redact {
+			pubKey = []byte("<redacted>\n")
+		}
+		files = append(files, supportFile{"id_ed25519.pub", pubKey})
+	}
+
+	files = append(files, supportFile{"system.txt", []byte(supportSystemInfo())})
+	files = append(files, supportFile{"gpu.txt", []byte(supportGPUInfo())})
+
+	if log, err := supportServerLogTail(); err == nil {
+		files = append(files, supportFile{"server.log", log})
+	}
+
+	if !redact {
+		files = append(files, supportFile{"environment.txt", []byte(supportEnvironment())})
+	}
+
+	return files, nil
+}
+
+func supportPublicKey() ([]byte, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(home, ".ollama", "id_ed25519.pub"))
+}
+
+func supportSystemInfo() string {
+	return fmt.Sprintf("os: %s\narch: %s\ngo: %s\ncpus: %d\n", runtime.GOOS, runtime.GOARCH, runtime.Version(), runtime.NumCPU())
+}
+
+func supportGPUInfo() string {
+	info := gpu.GetGPUInfo()
+	return fmt.Sprintf("library: %s\nvariant: %s\n", info.Library, info.Variant)
+}
+
+func supportServerLogTail() ([]byte, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	logPath := filepath.Join(home, ".ollama", "logs", "server.log")
+	f, err := os.Open(logPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	const tailBytes = 1 << 20 // 1MiB
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() > tailBytes {
+		if _, err := f.Seek(-tailBytes, io.SeekEnd); err != nil {
+			return nil, err
+		}
+	}
+
+	return io.ReadAll(f)
+}
+
+func supportEnvironment() string {
+	var sb strings.Builder
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, "OLLAMA_") {
+			sb.WriteString(e)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func writeSupportZip(w io.Writer, files []supportFile) error {
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		entry, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeSupportTarGz(w io.Writer, files []supportFile) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -0,0 +1,148 @@
+// Package arch replaces the old hardcoded darwin-only memory switch in
+// llm.New with a registry keyed by GGML architecture string, so memory
+// pre-flight checks and backend selection cover every architecture
+// Ollama knows about rather than just the llama family.
+package arch
+
+import "fmt"
+
+// Entry describes everything llm.New needs to size and route a model of
+// a given GGML architecture.
+type Entry struct {
+	// EstimateParams derives a parameter count from the element count of
+	// each named tensor in the GGUF container.
+	EstimateParams func(tensorElements map[string]uint64) int64
+
+	// MemoryMultiplier is bytes-per-parameter for each quantization
+	// format this architecture supports.
+	MemoryMultiplier map[string]float64
+
+	// KVCacheBytesPerToken is the minimum KV-cache footprint per context
+	// token, added on top of the weight size.
+	KVCacheBytesPerToken int64
+
+	// Backend names the llm backend that should serve this
+	// architecture, e.g. "llama", "bert", "rwkv".
+	Backend string
+}
+
+// sumTensors adds up the element counts of the tensors that make up the
+// bulk of a model's parameters, which is close enough for a memory
+// pre-flight check without decoding every layer.
+func sumTensors(tensorElements map[string]uint64) int64 {
+	var total uint64
+	for _, n := range tensorElements {
+		total += n
+	}
+	return int64(total)
+}
+
+// standardMultipliers covers the quant formats shared by every
+// llama.cpp-family architecture in the registry below.
+var standardMultipliers = map[string]float64{
+	"F32":  4,
+	"F16":  2,
+	"Q8_0": 1,
+	"Q5_K": 0.7,
+	"Q4_K": 0.6,
+	"Q4_0": 0.55,
+}
+
+// Registry maps a GGML architecture string (as reported by
+// ggml.ModelFamily) to how to size and route it.
+var Registry = map[string]Entry{
+	"llama": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 128 * 1024,
+		Backend:              "llama",
+	},
+	"falcon": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 96 * 1024,
+		Backend:              "llama",
+	},
+	"mpt": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 96 * 1024,
+		Backend:              "llama",
+	},
+	"gpt-neox": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 96 * 1024,
+		Backend:              "llama",
+	},
+	"stablelm": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 64 * 1024,
+		Backend:              "llama",
+	},
+	"bloom": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 96 * 1024,
+		Backend:              "llama",
+	},
+	"starcoder": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 96 * 1024,
+		Backend:              "llama",
+	},
+	"bert": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 0, // encoder-only, no autoregressive KV cache
+		Backend:              "bert",
+	},
+	"nomic-bert": {
+		EstimateParams:       sumTensors,
+		MemoryMultiplier:     standardMultipliers,
+		KVCacheBytesPerToken: 0,
+		Backend:              "bert",
+	},
+	"rwkv": {
+		EstimateParams:   sumTensors,
+		MemoryMultiplier: standardMultipliers,
+		// RWKV carries a constant-size RNN state rather than a
+		// per-token KV cache: the state is updated in place each step,
+		// not grown, so there's no per-token memory cost to account for.
+		KVCacheBytesPerToken: 0,
+		Backend:              "rwkv",
+	},
+}
+
+// RequiredMemory estimates the bytes of memory needed to load a model of
+// the given architecture and quantization at numCtx context length. The
+// second return value is false if architecture isn't in the registry.
+func RequiredMemory(architecture, quant string, tensorElements map[string]uint64, numCtx int) (int64, bool, error) {
+	entry, ok := Registry[architecture]
+	if !ok {
+		return 0, false, nil
+	}
+
+	multiplier, ok := entry.MemoryMultiplier[quant]
+	if !ok {
+		return 0, true, fmt.Errorf("arch: %s has no memory multiplier for quant format %q", architecture, quant)
+	}
+
+	params := entry.EstimateParams(tensorElements)
+	weights := int64(float64(params) * multiplier)
+	kvCache := entry.KVCacheBytesPerToken * int64(numCtx)
+
+	return weights + kvCache, true, nil
+}
+
+// Backend returns which llm backend should serve architecture, defaulting
+// to "llama" for anything not in the registry so unknown architectures
+// still get a best-effort attempt through the built-in path.
+func Backend(architecture string) string {
+	if entry, ok := Registry[architecture]; ok {
+		return entry.Backend
+	}
+	return "llama"
+}
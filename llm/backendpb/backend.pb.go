@@ -0,0 +1,71 @@
+// Package backendpb defines the wire types for the Backend gRPC service
+// in llm/backend.proto.
+//
+// These are hand-written plain structs, not protoc-gen-go output: they
+// don't implement proto.Message, so they ride over gRPC using the
+// jsonCodec in codec.go (registered under the "json" content-subtype)
+// rather than the default protobuf codec. If this service ever needs to
+// interoperate with a non-Go client, replace this file and
+// backend_grpc.pb.go with real protoc/protoc-gen-go-grpc output and drop
+// codec.go.
+package backendpb
+
+type CapabilitiesRequest struct{}
+
+type CapabilitiesResponse struct {
+	Formats           []string `json:"formats"`
+	SupportsEmbedding bool     `json:"supports_embedding"`
+}
+
+type PredictRequest struct {
+	Model      string   `json:"model"`
+	Adapters   []string `json:"adapters"`
+	Projectors []string `json:"projectors"`
+	Prompt     string   `json:"prompt"`
+	Options    []byte   `json:"options"`
+}
+
+type PredictResponse struct {
+	Content string `json:"content"`
+	Done    bool   `json:"done"`
+}
+
+type EmbeddingRequest struct {
+	Prompt string `json:"prompt"`
+}
+
+type EmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+type EmbeddingBatchRequest struct {
+	Prompts []string `json:"prompts"`
+}
+
+type Embedding struct {
+	Values []float64 `json:"values"`
+}
+
+type EmbeddingBatchResponse struct {
+	Embeddings []*Embedding `json:"embeddings"`
+}
+
+type EncodeRequest struct {
+	Text string `json:"text"`
+}
+
+type EncodeResponse struct {
+	Tokens []int64 `json:"tokens"`
+}
+
+type DecodeRequest struct {
+	Tokens []int64 `json:"tokens"`
+}
+
+type DecodeResponse struct {
+	Text string `json:"text"`
+}
+
+type CloseRequest struct{}
+
+type CloseResponse struct{}
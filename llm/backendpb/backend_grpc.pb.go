@@ -0,0 +1,109 @@
+// Hand-written client stub for the Backend service in llm/backend.proto.
+// This is not protoc-gen-go-grpc output (see the package doc comment in
+// backend.pb.go for why), so it's fair game to edit directly.
+
+package backendpb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+type BackendClient interface {
+	Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (Backend_PredictClient, error)
+	Embedding(ctx context.Context, in *EmbeddingRequest, opts ...grpc.CallOption) (*EmbeddingResponse, error)
+	EmbeddingBatch(ctx context.Context, in *EmbeddingBatchRequest, opts ...grpc.CallOption) (*EmbeddingBatchResponse, error)
+	Encode(ctx context.Context, in *EncodeRequest, opts ...grpc.CallOption) (*EncodeResponse, error)
+	Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error)
+	Close(ctx context.Context, in *CloseRequest, opts ...grpc.CallOption) (*CloseResponse, error)
+}
+
+type Backend_PredictClient interface {
+	Recv() (*PredictResponse, error)
+	grpc.ClientStream
+}
+
+type backendClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBackendClient(cc grpc.ClientConnInterface) BackendClient {
+	return &backendClient{cc}
+}
+
+func (c *backendClient) Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	out := new(CapabilitiesResponse)
+	if err := c.cc.Invoke(ctx, "/backendpb.Backend/Capabilities", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backendClient) Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (Backend_PredictClient, error) {
+	stream, err := c.cc.(*grpc.ClientConn).NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/backendpb.Backend/Predict", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &backendPredictClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type backendPredictClient struct {
+	grpc.ClientStream
+}
+
+func (x *backendPredictClient) Recv() (*PredictResponse, error) {
+	m := new(PredictResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *backendClient) Embedding(ctx context.Context, in *EmbeddingRequest, opts ...grpc.CallOption) (*EmbeddingResponse, error) {
+	out := new(EmbeddingResponse)
+	if err := c.cc.Invoke(ctx, "/backendpb.Backend/Embedding", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backendClient) EmbeddingBatch(ctx context.Context, in *EmbeddingBatchRequest, opts ...grpc.CallOption) (*EmbeddingBatchResponse, error) {
+	out := new(EmbeddingBatchResponse)
+	if err := c.cc.Invoke(ctx, "/backendpb.Backend/EmbeddingBatch", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backendClient) Encode(ctx context.Context, in *EncodeRequest, opts ...grpc.CallOption) (*EncodeResponse, error) {
+	out := new(EncodeResponse)
+	if err := c.cc.Invoke(ctx, "/backendpb.Backend/Encode", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backendClient) Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*DecodeResponse, error) {
+	out := new(DecodeResponse)
+	if err := c.cc.Invoke(ctx, "/backendpb.Backend/Decode", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backendClient) Close(ctx context.Context, in *CloseRequest, opts ...grpc.CallOption) (*CloseResponse, error) {
+	out := new(CloseResponse)
+	if err := c.cc.Invoke(ctx, "/backendpb.Backend/Close", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
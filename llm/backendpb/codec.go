@@ -0,0 +1,39 @@
+package backendpb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is registered as a gRPC content-subtype so plugin.go can pick
+// it with grpc.CallContentSubtype, instead of falling through to gRPC's
+// default codec, which requires proto.Message and would fail to marshal
+// the plain structs in backend.pb.go.
+const codecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("backendpb: marshal %T: %w", v, err)
+	}
+	return data, nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("backendpb: unmarshal %T: %w", v, err)
+	}
+	return nil
+}
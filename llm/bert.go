@@ -0,0 +1,92 @@
+package llm
+
+/*
+#cgo LDFLAGS: -lbert -lstdc++ -lm
+#include <stdlib.h>
+#include "bert.cpp/binding/binding.h"
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+
+	"github.com/jmorganca/ollama/api"
+)
+
+// bertServer implements LLM on top of bert.cpp, a lightweight
+// sentence-encoder runtime. It is selected in place of the full
+// llama.cpp server for models whose GGML metadata identifies them as a
+// BERT-family encoder, since those models are pure-embedding and gain
+// nothing from the decoder server's KV cache or sampling machinery.
+type bertServer struct {
+	ctx unsafe.Pointer
+}
+
+// newBertServer loads a BERT-family GGUF model for embedding inference.
+func newBertServer(model string, opts api.Options) (LLM, error) {
+	cModel := C.CString(model)
+	defer C.free(unsafe.Pointer(cModel))
+
+	ctx := C.bert_load_model(cModel, C.int(opts.NumThread))
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load bert model %s", model)
+	}
+
+	return &bertServer{ctx: unsafe.Pointer(ctx)}, nil
+}
+
+func (m *bertServer) Predict(_ context.Context, _ PredictOpts, _ func(PredictResult)) error {
+	return fmt.Errorf("bert: embeddings-only backend does not support predict")
+}
+
+func (m *bertServer) Embedding(ctx context.Context, prompt string) ([]float64, error) {
+	out, err := m.EmbeddingBatch(ctx, []string{prompt})
+	if err != nil {
+		return nil, err
+	}
+	return out[0], nil
+}
+
+// EmbeddingBatch embeds each of prompts against the loaded context.
+// bert.cpp's binding doesn't expose a batched embed entry point, so this
+// is a loop over bert_embed rather than a single amortized call; it
+// exists so callers have one place to embed a slice of prompts without
+// each writing that loop themselves.
+func (m *bertServer) EmbeddingBatch(ctx context.Context, prompts []string) ([][]float64, error) {
+	results := make([][]float64, len(prompts))
+	for i, prompt := range prompts {
+		cPrompt := C.CString(prompt)
+
+		var size C.int
+		vec := C.bert_embed((*C.bert_ctx)(m.ctx), cPrompt, &size)
+		C.free(unsafe.Pointer(cPrompt))
+		if vec == nil {
+			return nil, fmt.Errorf("bert: failed to embed prompt %d", i)
+		}
+
+		embedding := make([]float64, int(size))
+		floats := unsafe.Slice((*C.float)(vec), int(size))
+		for j, f := range floats {
+			embedding[j] = float64(f)
+		}
+		C.free(unsafe.Pointer(vec))
+
+		results[i] = embedding
+	}
+
+	return results, nil
+}
+
+func (m *bertServer) Encode(_ context.Context, _ string) ([]int, error) {
+	return nil, fmt.Errorf("bert: embeddings-only backend does not support encode")
+}
+
+func (m *bertServer) Decode(_ context.Context, _ []int) (string, error) {
+	return "", fmt.Errorf("bert: embeddings-only backend does not support decode")
+}
+
+func (m *bertServer) Close() {
+	C.bert_free_model((*C.bert_ctx)(m.ctx))
+}
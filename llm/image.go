@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageGenOpts are the parameters accepted by an ImageModel.Generate call.
+type ImageGenOpts struct {
+	Prompt         string
+	NegativePrompt string
+	Width          int
+	Height         int
+	Steps          int
+	Seed           int64
+}
+
+// ImageResult is a single chunk of image generation progress. Backends that
+// support incremental previews can call the callback more than once before
+// the final chunk, which has Done set to true and Image holding the
+// complete PNG.
+type ImageResult struct {
+	Image []byte // PNG-encoded
+	Done  bool
+}
+
+// ImageModel is the image-generation sibling of LLM.
+type ImageModel interface {
+	Generate(context.Context, ImageGenOpts, func(ImageResult)) error
+	Close()
+}
+
+// imageBackendName identifies which registered constructor NewImage
+// should use. Only one image backend exists today, but this is the seam
+// a second (e.g. a CUDA build of stablediffusion alongside the ncnn one)
+// would register under.
+const imageBackendName = "stablediffusion"
+
+// imageBackends is populated by backend packages registering themselves
+// at init time, the same pattern database/sql drivers use. llm can't
+// import the stablediffusion subpackage directly: stablediffusion
+// implements ImageModel, which means it already imports llm, and llm
+// importing it back would be a cycle. The binary's entrypoint is
+// expected to blank-import the backend package it wants available; see
+// cmd/cmd.go.
+var imageBackends = map[string]func(model string) (ImageModel, error){}
+
+// RegisterImageBackend makes a constructor available to NewImage under
+// name. Called from the registering backend's init().
+func RegisterImageBackend(name string, newModel func(model string) (ImageModel, error)) {
+	imageBackends[name] = newModel
+}
+
+// NewImage loads a Stable Diffusion checkpoint. Callers that hold a model
+// manifest should call this instead of New when the manifest's type
+// discriminator is "image".
+func NewImage(model string) (ImageModel, error) {
+	if _, err := os.Stat(model); err != nil {
+		return nil, err
+	}
+
+	if !isStableDiffusionCheckpoint(model) {
+		return nil, fmt.Errorf("%s does not look like a stable diffusion checkpoint", model)
+	}
+
+	newModel, ok := imageBackends[imageBackendName]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered; is it blank-imported?", imageBackendName)
+	}
+
+	return newModel(model)
+}
+
+// isStableDiffusionCheckpoint reports whether model looks like an
+// ncnn-stablediffusion checkpoint, which ships as a pair of files
+// (weights plus an ncnn ".param" network description) rather than the
+// single self-describing GGUF container a llama.cpp model uses.
+func isStableDiffusionCheckpoint(model string) bool {
+	paramPath := strings.TrimSuffix(model, filepath.Ext(model)) + ".param"
+	_, err := os.Stat(paramPath)
+	return err == nil
+}
@@ -12,11 +12,13 @@ import (
 	"github.com/jmorganca/ollama/api"
 	"github.com/jmorganca/ollama/format"
 	"github.com/jmorganca/ollama/gpu"
+	"github.com/jmorganca/ollama/llm/arch"
 )
 
 type LLM interface {
 	Predict(context.Context, PredictOpts, func(PredictResult)) error
 	Embedding(context.Context, string) ([]float64, error)
+	EmbeddingBatch(context.Context, []string) ([][]float64, error)
 	Encode(context.Context, string) ([]int, error)
 	Decode(context.Context, []int) (string, error)
 	Close()
@@ -27,6 +29,10 @@ func New(workDir, model string, adapters, projectors []string, opts api.Options)
 		return nil, err
 	}
 
+	if isStableDiffusionCheckpoint(model) {
+		return nil, fmt.Errorf("%s is a stable diffusion checkpoint; load it with llm.NewImage instead", model)
+	}
+
 	f, err := os.Open(model)
 	if err != nil {
 		return nil, err
@@ -38,40 +44,49 @@ func New(workDir, model string, adapters, projectors []string, opts api.Options)
 		return nil, err
 	}
 
-	if runtime.GOOS == "darwin" {
-		var requiredMemory int64
-		var f16Multiplier int64 = 2
-
-		switch ggml.ModelType() {
-		case "3B", "7B":
-			requiredMemory = 8 * format.GigaByte
-		case "13B":
-			requiredMemory = 16 * format.GigaByte
-		case "30B", "34B", "40B":
-			requiredMemory = 32 * format.GigaByte
-		case "47B":
-			requiredMemory = 48 * format.GigaByte
-		case "65B", "70B":
-			requiredMemory = 64 * format.GigaByte
-		case "180B":
-			requiredMemory = 128 * format.GigaByte
-			f16Multiplier = 4
-		}
+	architecture := ggml.ModelFamily()
+	gpuInfo := gpu.GetGPUInfo()
 
-		systemMemory := int64(memory.TotalMemory())
+	requiredMemory, known, err := arch.RequiredMemory(architecture, ggml.FileType(), ggml.Tensors(), opts.NumCtx)
+	if err != nil {
+		return nil, err
+	}
 
-		if ggml.FileType() == "F16" && requiredMemory*f16Multiplier > systemMemory {
-			return nil, fmt.Errorf("F16 model requires at least %s of memory", format.HumanBytes(requiredMemory))
-		} else if requiredMemory > systemMemory {
-			return nil, fmt.Errorf("model requires at least %s of memory", format.HumanBytes(requiredMemory))
+	// The registry sizes against system memory, which is only the right
+	// pool to check against on darwin's unified memory architecture or
+	// when there's no GPU to offload to; a discrete-GPU model can easily
+	// fit in VRAM while exceeding system RAM, and we don't have VRAM
+	// accounting here, so skip the hard reject in that case rather than
+	// produce a false-positive OOM rejection.
+	systemMemoryIsTheRelevantPool := runtime.GOOS == "darwin" || gpuInfo.Library == "cpu"
+
+	if known && systemMemoryIsTheRelevantPool {
+		systemMemory := int64(memory.TotalMemory())
+		if requiredMemory > systemMemory {
+			return nil, fmt.Errorf("%s model requires at least %s of memory", architecture, format.HumanBytes(requiredMemory))
 		}
+	} else if !known {
+		log.Printf("no memory sizing entry for architecture %q, skipping pre-flight check", architecture)
 	}
 
 	opts.NumGQA = 0
 	opts.RopeFrequencyBase = 0.0
 	opts.RopeFrequencyScale = 0.0
-	gpuInfo := gpu.GetGPUInfo()
-	return newLlmServer(gpuInfo, model, adapters, projectors, ggml.NumLayers(), opts)
+
+	switch arch.Backend(architecture) {
+	case "bert":
+		if srv, ok := tryPlugins(workDir, model, adapters, projectors, opts); ok {
+			return srv, nil
+		}
+		return newBertServer(model, opts)
+	case "rwkv":
+		if srv, ok := tryPlugins(workDir, model, adapters, projectors, opts); ok {
+			return srv, nil
+		}
+		return newRwkvServer(model, opts)
+	default:
+		return newLlmServer(workDir, gpuInfo, model, adapters, projectors, ggml.NumLayers(), opts)
+	}
 }
 
 // Give any native cgo implementations an opportunity to initialize
@@ -79,7 +94,11 @@ func Init(workdir string) error {
 	return nativeInit(workdir)
 }
 
-func newLlmServer(gpuInfo gpu.GpuInfo, model string, adapters, projectors []string, numLayers int64, opts api.Options) (extServer, error) {
+func newLlmServer(workDir string, gpuInfo gpu.GpuInfo, model string, adapters, projectors []string, numLayers int64, opts api.Options) (extServer, error) {
+	if srv, ok := tryPlugins(workDir, model, adapters, projectors, opts); ok {
+		return srv, nil
+	}
+
 	for _, shim := range getShims(gpuInfo) {
 		if shim == "default" {
 			break
@@ -0,0 +1,275 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/jmorganca/ollama/api"
+	"github.com/jmorganca/ollama/llm/backendpb"
+)
+
+// pluginPrefix is the naming convention external backend binaries must
+// follow to be discovered in a workdir, e.g. ollama-backend-rwkv.
+const pluginPrefix = "ollama-backend-"
+
+// discoverPlugins returns the paths of any external backend executables
+// found in workDir.
+func discoverPlugins(workDir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(workDir, pluginPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var plugins []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			plugins = append(plugins, m)
+		}
+	}
+
+	return plugins, nil
+}
+
+// sniffFormat inspects the first bytes of a model file to identify its
+// container format, used to route to the plugin that advertises support
+// for it.
+func sniffFormat(model string) (string, error) {
+	f, err := os.Open(model)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	magic := make([]byte, 9)
+	if _, err := io.ReadFull(f, magic); err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	switch {
+	case string(magic[:4]) == "GGUF":
+		return "gguf", nil
+	case string(magic[:4]) == "ggml" || string(magic[:4]) == "ggmf" || string(magic[:4]) == "ggjt":
+		return "ggml", nil
+	case magic[8] == '{':
+		// safetensors files begin with an 8-byte little-endian header
+		// length followed by a JSON header.
+		return "safetensors", nil
+	default:
+		return "unknown", nil
+	}
+}
+
+// tryPlugins attempts each backend plugin discovered in workDir in turn,
+// returning the first one that claims model. Called ahead of every
+// built-in backend — the llama.cpp shim loop in newLlmServer as well as
+// the bert/rwkv fallbacks in New — so a community-authored plugin gets a
+// chance to claim a model format before Ollama's own implementation does.
+func tryPlugins(workDir, model string, adapters, projectors []string, opts api.Options) (extServer, bool) {
+	plugins, err := discoverPlugins(workDir)
+	if err != nil {
+		log.Printf("Failed to scan %s for backend plugins: %s", workDir, err)
+		return nil, false
+	}
+
+	for _, plugin := range plugins {
+		srv, err := newPluginServer(plugin, model, adapters, projectors, opts)
+		if err == nil {
+			return srv, true
+		}
+		log.Printf("Plugin %s declined model %s: %s", plugin, model, err)
+	}
+
+	return nil, false
+}
+
+// pluginServer implements LLM by delegating to an out-of-process backend
+// binary over gRPC. This lets community-authored backends (RWKV, Mamba,
+// a vLLM shim, ...) run without being compiled into Ollama.
+type pluginServer struct {
+	cmd      *exec.Cmd
+	conn     *grpc.ClientConn
+	client   backendpb.BackendClient
+	sockPath string
+}
+
+// newPluginServer launches the backend at path, bound to a fresh UNIX
+// socket, and returns a client for it once it reports a model it can
+// serve.
+func newPluginServer(path, model string, adapters, projectors []string, opts api.Options) (extServer, error) {
+	sockPath := filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d.sock", filepath.Base(path), os.Getpid()))
+	os.Remove(sockPath)
+
+	cmd := exec.Command(path, "--socket", sockPath)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", path, err)
+	}
+
+	if err := waitForSocket(sockPath, 5*time.Second); err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("plugin %s did not come up: %w", path, err)
+	}
+
+	conn, err := grpc.Dial(
+		"unix://"+sockPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype("json")),
+	)
+	if err != nil {
+		cmd.Process.Kill()
+		return nil, err
+	}
+
+	client := backendpb.NewBackendClient(conn)
+
+	capsCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	caps, err := client.Capabilities(capsCtx, &backendpb.CapabilitiesRequest{})
+	cancel()
+	if err != nil {
+		conn.Close()
+		cmd.Process.Kill()
+		return nil, err
+	}
+
+	format, err := sniffFormat(model)
+	if err != nil {
+		conn.Close()
+		cmd.Process.Kill()
+		return nil, err
+	}
+
+	if !supportsFormat(caps.Formats, format) {
+		conn.Close()
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("plugin %s does not support format %q", path, format)
+	}
+
+	return &pluginServer{cmd: cmd, conn: conn, client: client, sockPath: sockPath}, nil
+}
+
+func supportsFormat(formats []string, format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func waitForSocket(path string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("timed out waiting for %s", path)
+}
+
+func (s *pluginServer) Predict(ctx context.Context, opts PredictOpts, fn func(PredictResult)) error {
+	encodedOpts, err := json.Marshal(opts.Options)
+	if err != nil {
+		return err
+	}
+
+	stream, err := s.client.Predict(ctx, &backendpb.PredictRequest{
+		Model:      opts.Model,
+		Adapters:   opts.Adapters,
+		Projectors: opts.Projectors,
+		Prompt:     opts.Prompt,
+		Options:    encodedOpts,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		fn(PredictResult{
+			Content: resp.Content,
+			Done:    resp.Done,
+		})
+
+		if resp.Done {
+			return nil
+		}
+	}
+}
+
+func (s *pluginServer) Embedding(ctx context.Context, prompt string) ([]float64, error) {
+	resp, err := s.client.Embedding(ctx, &backendpb.EmbeddingRequest{Prompt: prompt})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embedding, nil
+}
+
+func (s *pluginServer) EmbeddingBatch(ctx context.Context, prompts []string) ([][]float64, error) {
+	resp, err := s.client.EmbeddingBatch(ctx, &backendpb.EmbeddingBatchRequest{Prompts: prompts})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]float64, len(resp.Embeddings))
+	for i, e := range resp.Embeddings {
+		out[i] = e.Values
+	}
+	return out, nil
+}
+
+func (s *pluginServer) Encode(ctx context.Context, prompt string) ([]int, error) {
+	resp, err := s.client.Encode(ctx, &backendpb.EncodeRequest{Text: prompt})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]int, len(resp.Tokens))
+	for i, t := range resp.Tokens {
+		tokens[i] = int(t)
+	}
+	return tokens, nil
+}
+
+func (s *pluginServer) Decode(ctx context.Context, tokens []int) (string, error) {
+	encoded := make([]int64, len(tokens))
+	for i, t := range tokens {
+		encoded[i] = int64(t)
+	}
+
+	resp, err := s.client.Decode(ctx, &backendpb.DecodeRequest{Tokens: encoded})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+func (s *pluginServer) Close() {
+	if _, err := s.client.Close(context.Background(), &backendpb.CloseRequest{}); err != nil {
+		log.Printf("plugin close: %s", err)
+	}
+
+	s.conn.Close()
+	if s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+	os.Remove(s.sockPath)
+}
@@ -0,0 +1,147 @@
+package llm
+
+/*
+#cgo LDFLAGS: -lrwkv -lstdc++ -lm
+#include <stdlib.h>
+#include "rwkv.cpp/rwkv.h"
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"unsafe"
+
+	"github.com/jmorganca/ollama/api"
+)
+
+// rwkvServer implements LLM on top of rwkv.cpp. Unlike the llama.cpp
+// family, RWKV is a recurrent model: instead of an attention KV cache it
+// carries a fixed-size RNN state forward from one token to the next, so
+// generation cost and memory stay flat as the context grows.
+type rwkvServer struct {
+	ctx        unsafe.Pointer
+	tokenizer  *rwkvTokenizer
+	stateBytes int
+}
+
+// newRwkvServer loads an RWKV checkpoint and its companion 20B tokenizer,
+// which ships alongside the model rather than being embedded in the
+// GGUF metadata the way the llama.cpp vocab is.
+func newRwkvServer(model string, opts api.Options) (LLM, error) {
+	cModel := C.CString(model)
+	defer C.free(unsafe.Pointer(cModel))
+
+	ctx := C.rwkv_init_from_file(cModel, C.uint32_t(opts.NumThread))
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load rwkv model %s", model)
+	}
+
+	tokenizerPath := filepath.Join(filepath.Dir(model), "20B_tokenizer.json")
+	tokenizer, err := loadRwkvTokenizer(tokenizerPath)
+	if err != nil {
+		C.rwkv_free((*C.struct_rwkv_context)(ctx))
+		return nil, fmt.Errorf("failed to load rwkv tokenizer: %w", err)
+	}
+
+	return &rwkvServer{
+		ctx:        ctx,
+		tokenizer:  tokenizer,
+		stateBytes: int(C.rwkv_get_state_len((*C.struct_rwkv_context)(ctx))) * 4,
+	}, nil
+}
+
+func (m *rwkvServer) Predict(ctx context.Context, opts PredictOpts, fn func(PredictResult)) error {
+	tokens, err := m.tokenizer.Encode(opts.Prompt)
+	if err != nil {
+		return err
+	}
+
+	state := make([]float32, m.stateBytes/4)
+	logits := make([]float32, C.rwkv_get_logits_len((*C.struct_rwkv_context)(m.ctx)))
+
+	// prime the RNN state on the prompt tokens before sampling. The very
+	// first eval call must pass a NULL state_in so rwkv.cpp selects the
+	// model's real initial state rather than the zeroed buffer above.
+	evaluated := false
+	for i, token := range tokens {
+		if err := m.eval(token, i == 0, state, logits); err != nil {
+			return err
+		}
+		evaluated = true
+	}
+
+	for i := 0; i < opts.Options.NumPredict; i++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		next := sampleLogits(logits, opts.Options)
+		if next == m.tokenizer.EndOfText() {
+			break
+		}
+
+		piece, err := m.tokenizer.Decode([]int{next})
+		if err != nil {
+			return err
+		}
+
+		fn(PredictResult{Content: piece})
+
+		if err := m.eval(next, !evaluated, state, logits); err != nil {
+			return err
+		}
+		evaluated = true
+	}
+
+	fn(PredictResult{Done: true})
+	return nil
+}
+
+// eval threads the RNN state through one decode step: state is both an
+// input and an output, replacing the growing KV cache a transformer
+// would need for the same step. first must be true for the very first
+// eval call on a fresh context, which passes a NULL state_in so
+// rwkv.cpp selects the model's real initial state instead of the
+// zeroed buffer in state.
+func (m *rwkvServer) eval(token int, first bool, state, logits []float32) error {
+	var stateIn *C.float
+	if !first {
+		stateIn = (*C.float)(unsafe.Pointer(&state[0]))
+	}
+
+	ok := C.rwkv_eval(
+		(*C.struct_rwkv_context)(m.ctx),
+		C.uint32_t(token),
+		stateIn,
+		(*C.float)(unsafe.Pointer(&state[0])),
+		(*C.float)(unsafe.Pointer(&logits[0])),
+	)
+	if !bool(ok) {
+		return fmt.Errorf("rwkv: eval failed on token %d", token)
+	}
+	return nil
+}
+
+func (m *rwkvServer) Embedding(context.Context, string) ([]float64, error) {
+	return nil, fmt.Errorf("rwkv: backend does not support embeddings")
+}
+
+func (m *rwkvServer) EmbeddingBatch(context.Context, []string) ([][]float64, error) {
+	return nil, fmt.Errorf("rwkv: backend does not support embeddings")
+}
+
+func (m *rwkvServer) Encode(_ context.Context, prompt string) ([]int, error) {
+	return m.tokenizer.Encode(prompt)
+}
+
+func (m *rwkvServer) Decode(_ context.Context, tokens []int) (string, error) {
+	return m.tokenizer.Decode(tokens)
+}
+
+func (m *rwkvServer) Close() {
+	C.rwkv_free((*C.struct_rwkv_context)(m.ctx))
+}
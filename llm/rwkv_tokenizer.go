@@ -0,0 +1,268 @@
+package llm
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jmorganca/ollama/api"
+)
+
+// rwkvTokenizer implements the byte-level BPE tokenizer RWKV ships as
+// 20B_tokenizer.json (the same scheme GPT-2/GPT-NeoX use): raw bytes are
+// remapped to a set of printable unicode runes so every byte value has
+// a printable, mergeable representation, pre-tokenized with the standard
+// GPT-2 regex, and then merged greedily by pair rank.
+type rwkvTokenizer struct {
+	tokenToID  map[string]int
+	idToToken  map[int]string
+	byteToRune map[byte]rune
+	runeToByte map[rune]byte
+	mergeRank  map[[2]string]int
+	eot        int
+}
+
+type rwkvTokenizerFile struct {
+	Model struct {
+		Vocab  map[string]int `json:"vocab"`
+		Merges []string       `json:"merges"`
+	} `json:"model"`
+}
+
+var gpt2PretokenizePattern = regexp.MustCompile(`'s|'t|'re|'ve|'m|'ll|'d| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+`)
+
+func loadRwkvTokenizer(path string) (*rwkvTokenizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tf rwkvTokenizerFile
+	if err := json.Unmarshal(data, &tf); err != nil {
+		return nil, err
+	}
+
+	t := &rwkvTokenizer{
+		tokenToID: tf.Model.Vocab,
+		idToToken: make(map[int]string, len(tf.Model.Vocab)),
+		mergeRank: make(map[[2]string]int, len(tf.Model.Merges)),
+	}
+
+	for token, id := range t.tokenToID {
+		t.idToToken[id] = token
+	}
+
+	for rank, merge := range tf.Model.Merges {
+		parts := strings.SplitN(merge, " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		t.mergeRank[[2]string{parts[0], parts[1]}] = rank
+	}
+
+	t.byteToRune, t.runeToByte = gpt2ByteToUnicode()
+	t.eot = t.tokenToID["<|endoftext|>"]
+	return t, nil
+}
+
+func (t *rwkvTokenizer) EndOfText() int {
+	return t.eot
+}
+
+// Encode applies the GPT-2 pre-tokenizer regex, remaps each piece's raw
+// bytes into the byte-to-unicode alphabet, and BPE-merges it against the
+// ranked merge list before looking each resulting symbol up in the vocab.
+func (t *rwkvTokenizer) Encode(text string) ([]int, error) {
+	var tokens []int
+
+	for _, piece := range gpt2PretokenizePattern.FindAllString(text, -1) {
+		symbols := t.byteLevelSymbols(piece)
+		symbols = t.applyMerges(symbols)
+
+		for _, sym := range symbols {
+			if id, ok := t.tokenToID[sym]; ok {
+				tokens = append(tokens, id)
+				continue
+			}
+			// a symbol the merges never reduced to a vocab entry; fall
+			// back byte-by-byte rather than dropping it
+			for _, r := range sym {
+				if id, ok := t.tokenToID[string(r)]; ok {
+					tokens = append(tokens, id)
+				}
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// byteLevelSymbols remaps each UTF-8 byte of s to its byte-to-unicode
+// rune, returning one single-rune string per input byte as the initial
+// BPE symbol sequence.
+func (t *rwkvTokenizer) byteLevelSymbols(s string) []string {
+	symbols := make([]string, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		symbols = append(symbols, string(t.byteToRune[s[i]]))
+	}
+	return symbols
+}
+
+// applyMerges repeatedly merges the adjacent symbol pair with the lowest
+// rank until no ranked pair remains, the standard BPE encode loop.
+func (t *rwkvTokenizer) applyMerges(symbols []string) []string {
+	for len(symbols) > 1 {
+		bestRank := -1
+		bestIdx := -1
+
+		for i := 0; i < len(symbols)-1; i++ {
+			if rank, ok := t.mergeRank[[2]string{symbols[i], symbols[i+1]}]; ok {
+				if bestRank == -1 || rank < bestRank {
+					bestRank = rank
+					bestIdx = i
+				}
+			}
+		}
+
+		if bestIdx == -1 {
+			break
+		}
+
+		merged := make([]string, 0, len(symbols)-1)
+		merged = append(merged, symbols[:bestIdx]...)
+		merged = append(merged, symbols[bestIdx]+symbols[bestIdx+1])
+		merged = append(merged, symbols[bestIdx+2:]...)
+		symbols = merged
+	}
+
+	return symbols
+}
+
+func (t *rwkvTokenizer) Decode(tokens []int) (string, error) {
+	var sb strings.Builder
+	for _, token := range tokens {
+		for _, r := range t.idToToken[token] {
+			sb.WriteByte(t.runeToByte[r])
+		}
+	}
+	return sb.String(), nil
+}
+
+// gpt2ByteToUnicode builds the byte<->rune remap from the original GPT-2
+// BPE paper: printable ASCII/Latin-1 bytes map to themselves, and the
+// remaining (mostly control) byte values are assigned unused code points
+// starting at 256, so every byte has a distinct printable, mergeable
+// representation.
+func gpt2ByteToUnicode() (map[byte]rune, map[rune]byte) {
+	byteToRune := make(map[byte]rune, 256)
+
+	var bs []int
+	for _, r := range [][2]int{{'!', '~'}, {0xA1, 0xAC}, {0xAE, 0xFF}} {
+		for b := r[0]; b <= r[1]; b++ {
+			bs = append(bs, b)
+		}
+	}
+
+	n := 0
+	assigned := make(map[int]bool, len(bs))
+	for _, b := range bs {
+		assigned[b] = true
+	}
+
+	for b := 0; b < 256; b++ {
+		if assigned[b] {
+			byteToRune[byte(b)] = rune(b)
+			continue
+		}
+		byteToRune[byte(b)] = rune(256 + n)
+		n++
+	}
+
+	runeToByte := make(map[rune]byte, 256)
+	for b, r := range byteToRune {
+		runeToByte[r] = b
+	}
+
+	return byteToRune, runeToByte
+}
+
+// sampleLogits picks the next token from RWKV's raw output logits. Ollama
+// normally leaves sampling to llama.cpp, but RWKV has no such runtime on
+// the Go side, so a small temperature/top-p sampler lives here instead.
+func sampleLogits(logits []float32, opts api.Options) int {
+	temperature := opts.Temperature
+	if temperature <= 0 {
+		return argmax(logits)
+	}
+
+	probs := softmax(logits, temperature)
+
+	topP := opts.TopP
+	if topP <= 0 {
+		topP = 1.0
+	}
+
+	order := make([]int, len(probs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return probs[order[a]] > probs[order[b]] })
+
+	var cumulative float64
+	cutoff := len(order)
+	for i, idx := range order {
+		cumulative += float64(probs[idx])
+		if cumulative >= topP {
+			cutoff = i + 1
+			break
+		}
+	}
+
+	r := rand.Float64() * cumulative
+	var running float64
+	for _, idx := range order[:cutoff] {
+		running += float64(probs[idx])
+		if running >= r {
+			return idx
+		}
+	}
+
+	return order[0]
+}
+
+func argmax(logits []float32) int {
+	best := 0
+	for i, v := range logits {
+		if v > logits[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+func softmax(logits []float32, temperature float32) []float32 {
+	max := logits[0]
+	for _, v := range logits {
+		if v > max {
+			max = v
+		}
+	}
+
+	probs := make([]float32, len(logits))
+	var sum float64
+	for i, v := range logits {
+		p := math.Exp(float64((v - max) / temperature))
+		probs[i] = float32(p)
+		sum += p
+	}
+
+	for i := range probs {
+		probs[i] = float32(float64(probs[i]) / sum)
+	}
+
+	return probs
+}
@@ -0,0 +1,78 @@
+// Package stablediffusion implements llm.ImageModel on top of
+// ncnn-stablediffusion, mirroring the way the llm package wraps
+// llama.cpp for text generation.
+package stablediffusion
+
+/*
+#cgo LDFLAGS: -lstable-diffusion -lstdc++ -lm
+#include <stdlib.h>
+#include "binding/binding.h"
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+
+	"github.com/jmorganca/ollama/llm"
+)
+
+func init() {
+	llm.RegisterImageBackend("stablediffusion", func(model string) (llm.ImageModel, error) {
+		return New(model)
+	})
+}
+
+type Model struct {
+	ctx unsafe.Pointer
+}
+
+// New loads a Stable Diffusion checkpoint and returns an llm.ImageModel
+// backed by ncnn-stablediffusion.
+func New(model string) (*Model, error) {
+	cModel := C.CString(model)
+	defer C.free(unsafe.Pointer(cModel))
+
+	ctx := C.sd_load_model(cModel)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load stable diffusion model %s", model)
+	}
+
+	return &Model{ctx: unsafe.Pointer(ctx)}, nil
+}
+
+func (m *Model) Generate(ctx context.Context, opts llm.ImageGenOpts, fn func(llm.ImageResult)) error {
+	cPrompt := C.CString(opts.Prompt)
+	defer C.free(unsafe.Pointer(cPrompt))
+
+	cNegative := C.CString(opts.NegativePrompt)
+	defer C.free(unsafe.Pointer(cNegative))
+
+	var size C.int
+	png := C.sd_generate(
+		(*C.sd_ctx)(m.ctx),
+		cPrompt,
+		cNegative,
+		C.int(opts.Width),
+		C.int(opts.Height),
+		C.int(opts.Steps),
+		C.longlong(opts.Seed),
+		&size,
+	)
+	if png == nil {
+		return fmt.Errorf("stable diffusion generation failed")
+	}
+	defer C.free(unsafe.Pointer(png))
+
+	fn(llm.ImageResult{
+		Image: C.GoBytes(unsafe.Pointer(png), size),
+		Done:  true,
+	})
+
+	return nil
+}
+
+func (m *Model) Close() {
+	C.sd_free_model((*C.sd_ctx)(m.ctx))
+}